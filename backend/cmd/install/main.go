@@ -1,75 +1,68 @@
 // install is a CLI tool for first-time setup.
-// It reads configuration from environment variables (compatible with deploy/.env)
-// and calls setup.Install() directly without requiring a running HTTP server.
+// It reads configuration from an optional YAML/JSON file and from
+// environment variables (compatible with deploy/.env), then calls
+// setup.Install() directly without requiring a running HTTP server.
 //
 // Usage:
 //
 //	go run ./backend/cmd/install
+//	go run ./backend/cmd/install --config install.yaml
+//	go run ./backend/cmd/install --mode=upgrade
+//	go run ./backend/cmd/install --mode=check
 //
-// Required env vars (same as deploy/.env):
+// --mode selects the operation:
+//
+//	install (default)  bootstrap a brand-new deployment
+//	upgrade             apply pending migrations to an existing deployment;
+//	                    idempotent, safe to run on every deploy
+//	check               verify connectivity and migration state without
+//	                    changing anything; exits non-zero on drift, so it
+//	                    doubles as a Kubernetes init-container readiness check
+//
+// Configuration precedence is env vars over config file over built-in
+// defaults; see setup.LoadConfig for the full set of `yaml`/`env`/`default`
+// struct tags. Typical env vars (same as deploy/.env):
 //
 //	POSTGRES_USER, POSTGRES_PASSWORD, POSTGRES_DB, DATABASE_PORT
 //	REDIS_PORT, REDIS_PASSWORD
 //	ADMIN_EMAIL, ADMIN_PASSWORD
+//
+// REDIS_MODE selects the Redis topology (standalone, sentinel, cluster).
+// Sentinel deployments additionally require REDIS_MASTER_NAME and
+// REDIS_SENTINEL_HOSTS; cluster deployments require REDIS_CLUSTER_HOSTS.
+// ADMIN_PASSWORD_RESET=true rotates the admin password during --mode=upgrade.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"strconv"
 
 	"github.com/Wei-Shaw/sub2api/internal/setup"
 )
 
-func getenv(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return fallback
-}
+func main() {
+	configPath := flag.String("config", "", "path to a YAML or JSON config file (optional)")
+	mode := flag.String("mode", "install", "operation to run: install, upgrade, or check")
+	flag.Parse()
 
-func getenvInt(key string, fallback int) int {
-	if v := os.Getenv(key); v != "" {
-		if i, err := strconv.Atoi(v); err == nil {
-			return i
-		}
+	cfg, err := setup.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "install failed: %v\n", err)
+		os.Exit(1)
 	}
-	return fallback
-}
 
-func main() {
-	cfg := &setup.SetupConfig{
-		Database: setup.DatabaseConfig{
-			Host:     getenv("DATABASE_HOST", "localhost"),
-			Port:     getenvInt("DATABASE_PORT", 5432),
-			User:     getenv("POSTGRES_USER", "sub2api"),
-			Password: getenv("POSTGRES_PASSWORD", ""),
-			DBName:   getenv("POSTGRES_DB", "sub2api"),
-			SSLMode:  getenv("DATABASE_SSLMODE", "disable"),
-		},
-		Redis: setup.RedisConfig{
-			Host:      getenv("REDIS_HOST", "localhost"),
-			Port:      getenvInt("REDIS_PORT", 6379),
-			Password:  getenv("REDIS_PASSWORD", ""),
-			DB:        getenvInt("REDIS_DB", 0),
-			EnableTLS: getenv("REDIS_ENABLE_TLS", "false") == "true",
-		},
-		Admin: setup.AdminConfig{
-			Email:    getenv("ADMIN_EMAIL", "admin@sub2api.local"),
-			Password: getenv("ADMIN_PASSWORD", ""),
-		},
-		Server: setup.ServerConfig{
-			Host: getenv("SERVER_HOST", "0.0.0.0"),
-			Port: getenvInt("SERVER_PORT", 8080),
-			Mode: getenv("SERVER_MODE", "debug"),
-		},
-		JWT: setup.JWTConfig{
-			Secret:     getenv("JWT_SECRET", ""),
-			ExpireHour: getenvInt("JWT_EXPIRE_HOUR", 24),
-		},
+	switch *mode {
+	case "install":
+		err = setup.Install(cfg)
+	case "upgrade":
+		err = setup.Upgrade(cfg)
+	case "check":
+		err = setup.Check(cfg)
+	default:
+		err = fmt.Errorf("unknown --mode %q (want install, upgrade, or check)", *mode)
 	}
-
-	if err := setup.Install(cfg); err != nil {
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "install failed: %v\n", err)
 		os.Exit(1)
 	}
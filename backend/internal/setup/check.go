@@ -0,0 +1,53 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Check verifies that cfg's database and Redis are reachable and that no
+// migrations are pending, without changing any state. It returns a non-nil
+// error the moment it finds drift, which callers (notably `install
+// --mode=check` running as a Kubernetes init-container) should treat as a
+// signal to fail their readiness probe.
+func Check(cfg *SetupConfig) error {
+	db, err := openDatabase(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rdb, err := openRedis(cfg.Redis)
+	if err != nil {
+		return err
+	}
+	defer rdb.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// pendingMigrations would create schema_migrations on a fresh database,
+	// so its absence is checked first and reported as every migration pending.
+	var tracked bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&tracked); err != nil {
+		return fmt.Errorf("setup: check migration state: %w", err)
+	}
+	if !tracked {
+		all, err := loadMigrations()
+		if err != nil {
+			return fmt.Errorf("setup: check migration state: %w", err)
+		}
+		return fmt.Errorf("setup: %d pending migration(s), run --mode=upgrade", len(all))
+	}
+
+	pending, err := pendingMigrations(ctx, db)
+	if err != nil {
+		return fmt.Errorf("setup: check migration state: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("setup: %d pending migration(s), run --mode=upgrade", len(pending))
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package setup
+
+// DatabaseConfig holds the connection parameters for the Postgres instance
+// used by sub2api.
+type DatabaseConfig struct {
+	Host     string `yaml:"host" json:"host" env:"DATABASE_HOST" default:"localhost"`
+	Port     int    `yaml:"port" json:"port" env:"DATABASE_PORT" default:"5432"`
+	User     string `yaml:"user" json:"user" env:"POSTGRES_USER" default:"sub2api"`
+	Password string `yaml:"password" json:"password" env:"POSTGRES_PASSWORD" default:""`
+	DBName   string `yaml:"db_name" json:"db_name" env:"POSTGRES_DB" default:"sub2api"`
+	SSLMode  string `yaml:"ssl_mode" json:"ssl_mode" env:"DATABASE_SSLMODE" default:"disable"`
+
+	// TLS material, passed to the driver whenever set, whatever SSLMode is.
+	// InsecureSkipVerify turns any SSLMode other than disable into require.
+	CACertFile         string `yaml:"tls_ca_cert_file" json:"tls_ca_cert_file" env:"DATABASE_TLS_CA" default:""`
+	CertFile           string `yaml:"tls_cert_file" json:"tls_cert_file" env:"DATABASE_TLS_CERT" default:""`
+	KeyFile            string `yaml:"tls_key_file" json:"tls_key_file" env:"DATABASE_TLS_KEY" default:""`
+	InsecureSkipVerify bool   `yaml:"tls_insecure_skip_verify" json:"tls_insecure_skip_verify" env:"DATABASE_TLS_INSECURE_SKIP_VERIFY" default:"false"`
+}
+
+// AdminConfig describes the initial administrator account created during
+// installation.
+type AdminConfig struct {
+	Email    string `yaml:"email" json:"email" env:"ADMIN_EMAIL" default:"admin@sub2api.local"`
+	Password string `yaml:"password" json:"password" env:"ADMIN_PASSWORD" default:""`
+
+	// ResetPassword rotates the admin password on the next `--mode=upgrade`
+	// run instead of leaving an already-provisioned admin account alone.
+	ResetPassword bool `yaml:"reset_password" json:"reset_password" env:"ADMIN_PASSWORD_RESET" default:"false"`
+}
+
+// ServerConfig mirrors the HTTP server settings applied at runtime.
+type ServerConfig struct {
+	Host string `yaml:"host" json:"host" env:"SERVER_HOST" default:"0.0.0.0"`
+	Port int    `yaml:"port" json:"port" env:"SERVER_PORT" default:"8080"`
+	Mode string `yaml:"mode" json:"mode" env:"SERVER_MODE" default:"debug"`
+}
+
+// JWTConfig configures token signing for the installed instance.
+type JWTConfig struct {
+	Secret     string `yaml:"secret" json:"secret" env:"JWT_SECRET" default:""`
+	ExpireHour int    `yaml:"expire_hour" json:"expire_hour" env:"JWT_EXPIRE_HOUR" default:"24"`
+}
+
+// SetupConfig aggregates everything the installer needs to bootstrap a new
+// sub2api deployment.
+type SetupConfig struct {
+	Database DatabaseConfig `yaml:"database" json:"database"`
+	Redis    RedisConfig    `yaml:"redis" json:"redis"`
+	Admin    AdminConfig    `yaml:"admin" json:"admin"`
+	Server   ServerConfig   `yaml:"server" json:"server"`
+	JWT      JWTConfig      `yaml:"jwt" json:"jwt"`
+}
@@ -0,0 +1,133 @@
+package setup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig builds a SetupConfig from, in increasing order of precedence,
+// struct field defaults, an optional config file at path, and environment
+// variables. Config files may be YAML or JSON, selected by extension
+// (.yaml/.yml or .json); pass an empty path to skip file loading entirely.
+//
+// Field-level struct tags on SetupConfig and its nested config structs drive
+// all three sources: `yaml:"..."` and `json:"..."` (kept identical to each
+// other) name the file key for their respective format, `env:"..."` names
+// the environment variable, and `default:"..."` supplies the fallback value.
+func LoadConfig(path string) (*SetupConfig, error) {
+	cfg := &SetupConfig{}
+	applyDefaults(reflect.ValueOf(cfg).Elem())
+
+	if path != "" {
+		if err := loadConfigFile(path, cfg); err != nil {
+			return nil, fmt.Errorf("setup: load config file: %w", err)
+		}
+	}
+
+	applyEnv(reflect.ValueOf(cfg).Elem())
+
+	return cfg, nil
+}
+
+func loadConfigFile(path string, cfg *SetupConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		return json.Unmarshal(data, cfg)
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file extension %q (want .yaml, .yml or .json)", ext)
+	}
+}
+
+// applyDefaults walks v recursively, setting each field from its `default`
+// struct tag when present.
+func applyDefaults(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct {
+			applyDefaults(fv)
+			continue
+		}
+
+		def, ok := field.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		setFieldFromString(fv, def)
+	}
+}
+
+// applyEnv walks v recursively, overriding each field whose `env` struct tag
+// names a set environment variable.
+func applyEnv(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct {
+			applyEnv(fv)
+			continue
+		}
+
+		key, ok := field.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		if val, present := os.LookupEnv(key); present {
+			setFieldFromString(fv, val)
+		}
+	}
+}
+
+func setFieldFromString(fv reflect.Value, raw string) {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		if raw == "" {
+			return
+		}
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			fv.SetInt(n)
+		}
+	case reflect.Bool:
+		if raw == "" {
+			return
+		}
+		if b, err := strconv.ParseBool(raw); err == nil {
+			fv.SetBool(b)
+		}
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return
+		}
+		if raw == "" {
+			return
+		}
+		parts := strings.Split(raw, ",")
+		hosts := make([]string, 0, len(parts))
+		for _, p := range parts {
+			if p = strings.TrimSpace(p); p != "" {
+				hosts = append(hosts, p)
+			}
+		}
+		fv.Set(reflect.ValueOf(hosts))
+	}
+}
@@ -0,0 +1,167 @@
+package setup
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migration is a single versioned SQL file. Filenames follow the
+// goose-style convention "NNNN_description.sql", with the up/down halves of
+// the file separated by "-- +up" / "-- +down" markers.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read embedded migrations: %w", err)
+	}
+
+	migrations := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		version, err := parseMigrationVersion(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		up, down := splitMigration(string(data))
+		migrations = append(migrations, migration{version: version, name: entry.Name(), up: up, down: down})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+func parseMigrationVersion(filename string) (int, error) {
+	prefix, _, ok := strings.Cut(filename, "_")
+	if !ok {
+		return 0, fmt.Errorf("migration %q does not follow the NNNN_description.sql naming convention", filename)
+	}
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, fmt.Errorf("migration %q has a non-numeric version prefix: %w", filename, err)
+	}
+	return version, nil
+}
+
+func splitMigration(contents string) (up, down string) {
+	const upMarker, downMarker = "-- +up", "-- +down"
+
+	upIdx := strings.Index(contents, upMarker)
+	downIdx := strings.Index(contents, downMarker)
+	if upIdx == -1 || downIdx == -1 {
+		return contents, ""
+	}
+	return strings.TrimSpace(contents[upIdx+len(upMarker) : downIdx]), strings.TrimSpace(contents[downIdx+len(downMarker):])
+}
+
+// ensureMigrationsTable creates the schema_migrations bookkeeping table if
+// it does not already exist. A fresh database thus starts with an empty
+// table, and every embedded migration counts as pending.
+func ensureMigrationsTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`)
+	return err
+}
+
+func appliedMigrationVersions(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// pendingMigrations returns the embedded migrations that have not yet been
+// recorded in schema_migrations, in version order.
+func pendingMigrations(ctx context.Context, db *sql.DB) ([]migration, error) {
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return nil, fmt.Errorf("ensure schema_migrations table: %w", err)
+	}
+
+	all, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrationVersions(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("read applied migrations: %w", err)
+	}
+
+	pending := make([]migration, 0, len(all))
+	for _, m := range all {
+		if !applied[m.version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// runMigrations applies every pending migration in a single transaction per
+// file, recording its version in schema_migrations as it goes. It is safe to
+// call repeatedly: once a version is recorded it is never re-applied.
+func runMigrations(ctx context.Context, db *sql.DB) error {
+	pending, err := pendingMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("begin migration %s: %w", m.name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, m.up); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("apply migration %s: %w", m.name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, m.version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("record migration %s: %w", m.name, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package setup
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis deployment topologies supported by RedisConfig.Mode.
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+// RedisConfig describes how to reach the Redis deployment backing sub2api.
+// Mode selects the topology: a plain single-node instance, a Sentinel-managed
+// failover group, or a Cluster. SentinelHosts/ClusterHosts are only consulted
+// for their respective modes; Host/Port are used for RedisModeStandalone.
+type RedisConfig struct {
+	Mode      string `yaml:"mode" json:"mode" env:"REDIS_MODE" default:"standalone"`
+	Host      string `yaml:"host" json:"host" env:"REDIS_HOST" default:"localhost"`
+	Port      int    `yaml:"port" json:"port" env:"REDIS_PORT" default:"6379"`
+	Password  string `yaml:"password" json:"password" env:"REDIS_PASSWORD" default:""`
+	DB        int    `yaml:"db" json:"db" env:"REDIS_DB" default:"0"`
+	EnableTLS bool   `yaml:"enable_tls" json:"enable_tls" env:"REDIS_ENABLE_TLS" default:"false"`
+
+	// TLS/mTLS material, consulted when EnableTLS is set.
+	CACertFile         string `yaml:"tls_ca_cert_file" json:"tls_ca_cert_file" env:"REDIS_TLS_CA" default:""`
+	CertFile           string `yaml:"tls_cert_file" json:"tls_cert_file" env:"REDIS_TLS_CERT" default:""`
+	KeyFile            string `yaml:"tls_key_file" json:"tls_key_file" env:"REDIS_TLS_KEY" default:""`
+	InsecureSkipVerify bool   `yaml:"tls_insecure_skip_verify" json:"tls_insecure_skip_verify" env:"REDIS_TLS_INSECURE_SKIP_VERIFY" default:"false"`
+
+	// Sentinel-mode fields.
+	MasterName       string   `yaml:"master_name" json:"master_name" env:"REDIS_MASTER_NAME" default:""`
+	SentinelHosts    []string `yaml:"sentinel_hosts" json:"sentinel_hosts" env:"REDIS_SENTINEL_HOSTS"`
+	SentinelUsername string   `yaml:"sentinel_username" json:"sentinel_username" env:"REDIS_SENTINEL_USER" default:""`
+	SentinelPassword string   `yaml:"sentinel_password" json:"sentinel_password" env:"REDIS_SENTINEL_PASSWORD" default:""`
+
+	// Cluster-mode fields.
+	ClusterHosts []string `yaml:"cluster_hosts" json:"cluster_hosts" env:"REDIS_CLUSTER_HOSTS"`
+}
+
+// newRedisClient builds a redis.UniversalClient for cfg.Mode, one of the
+// standalone/sentinel/cluster deployments installers need to support. Each
+// mode constructs its own concrete go-redis client type directly (Client,
+// FailoverClient, ClusterClient) rather than going through
+// redis.NewUniversalClient: that helper infers the topology from
+// len(Addrs)/MasterName, which silently falls back to a plain standalone
+// client for a cluster bootstrapped from a single seed host — a normal way
+// to start a Redis Cluster, since one seed is enough to discover the rest.
+// Deciding the client type from cfg.Mode instead means that heuristic never
+// gets a vote.
+func newRedisClient(cfg RedisConfig) (redis.UniversalClient, error) {
+	var tlsConfig *tls.Config
+	if cfg.EnableTLS {
+		var err error
+		if tlsConfig, err = buildTLSConfig(cfg.CACertFile, cfg.CertFile, cfg.KeyFile, cfg.InsecureSkipVerify); err != nil {
+			return nil, fmt.Errorf("setup: build redis tls config: %w", err)
+		}
+	}
+
+	switch cfg.Mode {
+	case "", RedisModeStandalone:
+		return redis.NewClient(&redis.Options{
+			Addr:      fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			Password:  cfg.Password,
+			DB:        cfg.DB,
+			TLSConfig: tlsConfig,
+		}), nil
+	case RedisModeSentinel:
+		if cfg.MasterName == "" {
+			return nil, fmt.Errorf("setup: REDIS_MASTER_NAME is required in sentinel mode")
+		}
+		if len(cfg.SentinelHosts) == 0 {
+			return nil, fmt.Errorf("setup: REDIS_SENTINEL_HOSTS is required in sentinel mode")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.SentinelHosts,
+			SentinelUsername: cfg.SentinelUsername,
+			SentinelPassword: cfg.SentinelPassword,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			TLSConfig:        tlsConfig,
+		}), nil
+	case RedisModeCluster:
+		if len(cfg.ClusterHosts) == 0 {
+			return nil, fmt.Errorf("setup: REDIS_CLUSTER_HOSTS is required in cluster mode")
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     cfg.ClusterHosts,
+			Password:  cfg.Password,
+			TLSConfig: tlsConfig,
+		}), nil
+	default:
+		return nil, fmt.Errorf("setup: unknown redis mode %q", cfg.Mode)
+	}
+}
@@ -0,0 +1,196 @@
+package setup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// setupTestBackends returns database and Redis configs to run Install
+// against. It reuses TESTS_DB_DSN / TESTS_REDIS_URL when set (so CI can
+// point at its own services) and otherwise spins up ephemeral Postgres and
+// Redis containers via dockertest, torn down when the test finishes.
+func setupTestBackends(t *testing.T) (DatabaseConfig, RedisConfig) {
+	t.Helper()
+
+	if dsn := os.Getenv("TESTS_DB_DSN"); dsn != "" {
+		db, err := DatabaseConfigFromDSN(dsn)
+		if err != nil {
+			t.Fatalf("parse TESTS_DB_DSN: %v", err)
+		}
+		return db, redisConfigFromEnv(t)
+	}
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		t.Fatalf("connect to docker: %v", err)
+	}
+
+	pgResource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "16-alpine",
+		Env: []string{
+			"POSTGRES_USER=sub2api",
+			"POSTGRES_PASSWORD=sub2api",
+			"POSTGRES_DB=sub2api",
+		},
+	}, func(hc *docker.HostConfig) { hc.AutoRemove = true })
+	if err != nil {
+		t.Fatalf("start postgres container: %v", err)
+	}
+	t.Cleanup(func() { pool.Purge(pgResource) })
+
+	redisResource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "redis",
+		Tag:        "7-alpine",
+	}, func(hc *docker.HostConfig) { hc.AutoRemove = true })
+	if err != nil {
+		t.Fatalf("start redis container: %v", err)
+	}
+	t.Cleanup(func() { pool.Purge(redisResource) })
+
+	dbCfg := DatabaseConfig{
+		Host:     "localhost",
+		Port:     mustAtoi(t, pgResource.GetPort("5432/tcp")),
+		User:     "sub2api",
+		Password: "sub2api",
+		DBName:   "sub2api",
+		SSLMode:  "disable",
+	}
+	redisCfg := RedisConfig{
+		Mode: RedisModeStandalone,
+		Host: "localhost",
+		Port: mustAtoi(t, redisResource.GetPort("6379/tcp")),
+	}
+
+	pool.MaxWait = 30 * time.Second
+	if err := pool.Retry(func() error {
+		db, err := sql.Open("postgres", databaseDSN(dbCfg))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		return db.Ping()
+	}); err != nil {
+		t.Fatalf("postgres did not become ready: %v", err)
+	}
+	if err := pool.Retry(func() error {
+		rdb, err := newRedisClient(redisCfg)
+		if err != nil {
+			return err
+		}
+		defer rdb.Close()
+		return rdb.Ping(context.Background()).Err()
+	}); err != nil {
+		t.Fatalf("redis did not become ready: %v", err)
+	}
+
+	return dbCfg, redisCfg
+}
+
+func redisConfigFromEnv(t *testing.T) RedisConfig {
+	t.Helper()
+	url := os.Getenv("TESTS_REDIS_URL")
+	if url == "" {
+		t.Fatal("TESTS_REDIS_URL must be set alongside TESTS_DB_DSN")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("parse TESTS_REDIS_URL: %v", err)
+	}
+	host, port, err := net.SplitHostPort(opts.Addr)
+	if err != nil {
+		t.Fatalf("parse TESTS_REDIS_URL address: %v", err)
+	}
+	return RedisConfig{Mode: RedisModeStandalone, Host: host, Port: mustAtoi(t, port), Password: opts.Password, DB: opts.DB}
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	t.Helper()
+	var n int
+	if _, err := fmt.Sscanf(s, "%d", &n); err != nil {
+		t.Fatalf("parse port %q: %v", s, err)
+	}
+	return n
+}
+
+func TestInstall_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping dockertest-backed integration test in -short mode")
+	}
+
+	dbCfg, redisCfg := setupTestBackends(t)
+	cfg := &SetupConfig{
+		Database: dbCfg,
+		Redis:    redisCfg,
+		Admin:    AdminConfig{Email: "admin@sub2api.local", Password: "correct-horse-battery-staple"},
+		Server:   ServerConfig{Host: "0.0.0.0", Port: 8080, Mode: "debug"},
+		JWT:      JWTConfig{},
+	}
+
+	if err := Install(cfg); err != nil {
+		t.Fatalf("first Install() failed: %v", err)
+	}
+	if cfg.JWT.Secret == "" {
+		t.Error("Install() left JWT.Secret empty; expected a generated secret")
+	}
+
+	db, err := sql.Open("postgres", databaseDSN(cfg.Database))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	var hash string
+	if err := db.QueryRow(`SELECT password_hash FROM users WHERE email = $1`, cfg.Admin.Email).Scan(&hash); err != nil {
+		t.Fatalf("query admin user: %v", err)
+	}
+	if cost, err := bcrypt.Cost([]byte(hash)); err != nil {
+		t.Fatalf("bcrypt.Cost: %v", err)
+	} else if cost != bcrypt.DefaultCost {
+		t.Errorf("admin password hash cost = %d, want %d", cost, bcrypt.DefaultCost)
+	}
+
+	secretAfterFirstInstall := cfg.JWT.Secret
+
+	// Build a second, independent SetupConfig rather than reusing cfg: in
+	// real use, install/upgrade is invoked as a fresh process each time
+	// (e.g. a Kubernetes init-container on every deploy), with no JWT_SECRET
+	// carried over from the previous run. This is what would have caught
+	// ensureJWTSecret minting a new throwaway secret on every invocation
+	// instead of persisting and reusing it.
+	cfg2 := &SetupConfig{
+		Database: dbCfg,
+		Redis:    redisCfg,
+		Admin:    AdminConfig{Email: "admin@sub2api.local", Password: "correct-horse-battery-staple"},
+		Server:   ServerConfig{Host: "0.0.0.0", Port: 8080, Mode: "debug"},
+		JWT:      JWTConfig{},
+	}
+
+	if err := Install(cfg2); err != nil {
+		t.Fatalf("second Install() failed (expected idempotent): %v", err)
+	}
+
+	var userCount int
+	if err := db.QueryRow(`SELECT count(*) FROM users WHERE email = $1`, cfg.Admin.Email).Scan(&userCount); err != nil {
+		t.Fatalf("count admin users: %v", err)
+	}
+	if userCount != 1 {
+		t.Errorf("admin user count after re-install = %d, want 1", userCount)
+	}
+	if cfg2.JWT.Secret == "" {
+		t.Error("second Install() (fresh process, empty JWT_SECRET) left JWT.Secret empty")
+	}
+	if cfg2.JWT.Secret != secretAfterFirstInstall {
+		t.Error("second Install() (fresh process, empty JWT_SECRET) minted a new JWT secret instead of reusing the persisted one")
+	}
+}
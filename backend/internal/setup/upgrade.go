@@ -0,0 +1,45 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Upgrade brings an existing sub2api deployment up to date: it applies any
+// pending schema migrations and, unless cfg.Admin.ResetPassword is set,
+// leaves the existing admin account alone. It never fails merely because the
+// database and Redis are already provisioned, which makes it safe to run on
+// every deploy (e.g. as a Kubernetes init-container with --mode=upgrade).
+func Upgrade(cfg *SetupConfig) error {
+	db, err := openDatabase(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rdb, err := openRedis(cfg.Redis)
+	if err != nil {
+		return err
+	}
+	defer rdb.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := runMigrations(ctx, db); err != nil {
+		return fmt.Errorf("setup: run migrations: %w", err)
+	}
+
+	if err := ensureJWTSecret(ctx, db, &cfg.JWT); err != nil {
+		return fmt.Errorf("setup: generate jwt secret: %w", err)
+	}
+
+	if cfg.Admin.ResetPassword {
+		if err := resetAdminPassword(ctx, db, cfg.Admin); err != nil {
+			return fmt.Errorf("setup: rotate admin password: %w", err)
+		}
+	}
+
+	return nil
+}